@@ -0,0 +1,245 @@
+// Package asm implements a tiny text assembler and disassembler for the
+// Monkey VM's bytecode, in the spirit of the EVM `compile` subcommand: a
+// human-writable mnemonic form of the opcodes in package code that round
+// trips through compiler.Bytecode. It exists so the compiler's output can
+// be inspected, hand-tuned, and unit tested without going through the
+// lexer and parser.
+//
+// A program is a sequence of instructions, labels, and constant
+// directives, one per line:
+//
+//	OpConstant 0
+//	OpConstant 1
+//	OpAdd
+//	.loop:
+//	OpGetLocal 0
+//	OpJumpNotTruthy .done
+//	OpJump .loop
+//	.done:
+//	OpReturnValue
+//
+//	.const 42
+//	.string "hi"
+//
+// `;` starts a comment that runs to the end of the line. `.const` and
+// `.string` directives append to the constant pool in the order they
+// appear; OpConstant operands are plain indices into that pool.
+package asm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"monkey-lang/code"
+	"monkey-lang/compiler"
+	"monkey-lang/object"
+)
+
+// mnemonics maps opcode names (as they appear in source) to their Opcode
+// byte value. It's built once from code.Lookup, since package code only
+// exposes a byte->Definition direction.
+var mnemonics = buildMnemonics()
+
+func buildMnemonics() map[string]code.Opcode {
+	m := make(map[string]code.Opcode)
+	for b := 0; b <= 0xff; b++ {
+		op := code.Opcode(b)
+		def, err := code.Lookup(op)
+		if err != nil {
+			continue
+		}
+		m[def.Name] = op
+	}
+	return m
+}
+
+// Error reports an assembly failure together with the 1-based source line
+// it occurred on.
+type Error struct {
+	Line int
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+type instruction struct {
+	line     int
+	op       code.Opcode
+	operands []string // raw operand tokens; may be label references
+	addr     int      // byte offset this instruction is emitted at
+}
+
+// Assemble parses src and emits the equivalent compiler.Bytecode.
+func Assemble(src string) (*compiler.Bytecode, error) {
+	a := &assembler{labels: map[string]int{}}
+	if err := a.scan(src); err != nil {
+		return nil, err
+	}
+	return a.emit()
+}
+
+type assembler struct {
+	instructions []instruction
+	constants    []object.Object
+	labels       map[string]int
+}
+
+// scan is the assembler's first pass: it strips comments, records label
+// addresses and constant-pool directives, and records each instruction's
+// opcode and raw operand tokens without resolving label references yet
+// (a later label definition must still be usable by an earlier jump).
+func (a *assembler) scan(src string) error {
+	addr := 0
+
+	for i, raw := range strings.Split(src, "\n") {
+		lineNo := i + 1
+		line := stripComment(raw)
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		// Label lines (".name:") are checked first, and directives are
+		// matched on their whole first word rather than as a prefix, so a
+		// label like ".constants:" or ".stringify:" is registered as a label
+		// instead of being rejected as a malformed .const/.string directive.
+		directive, _, _ := strings.Cut(line, " ")
+
+		switch {
+		case strings.HasPrefix(line, ".") && strings.HasSuffix(line, ":"):
+			label := strings.TrimSuffix(strings.TrimPrefix(line, "."), ":")
+			if label == "" {
+				return &Error{lineNo, "empty label"}
+			}
+			if _, exists := a.labels[label]; exists {
+				return &Error{lineNo, fmt.Sprintf("label %q redefined", label)}
+			}
+			a.labels[label] = addr
+
+		case directive == ".const":
+			fields := strings.Fields(line)
+			if len(fields) != 2 {
+				return &Error{lineNo, "expected `.const <integer>`"}
+			}
+			n, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return &Error{lineNo, fmt.Sprintf("invalid integer constant %q", fields[1])}
+			}
+			a.constants = append(a.constants, &object.Integer{Value: n})
+
+		case directive == ".string":
+			s, err := parseQuoted(strings.TrimSpace(strings.TrimPrefix(line, ".string")))
+			if err != nil {
+				return &Error{lineNo, err.Error()}
+			}
+			a.constants = append(a.constants, &object.String{Value: s})
+
+		default:
+			fields := strings.Fields(line)
+			name := fields[0]
+			op, ok := mnemonics[name]
+			if !ok {
+				return &Error{lineNo, fmt.Sprintf("unknown opcode %q", name)}
+			}
+			def, err := code.Lookup(op)
+			if err != nil {
+				return &Error{lineNo, err.Error()}
+			}
+			operands := fields[1:]
+			if len(operands) != len(def.OperandWidths) {
+				return &Error{lineNo, fmt.Sprintf("%s takes %d operand(s), got %d", name, len(def.OperandWidths), len(operands))}
+			}
+			a.instructions = append(a.instructions, instruction{
+				line:     lineNo,
+				op:       op,
+				operands: operands,
+				addr:     addr,
+			})
+			addr += instructionWidth(def)
+		}
+	}
+
+	return nil
+}
+
+func instructionWidth(def *code.Definition) int {
+	width := 1
+	for _, w := range def.OperandWidths {
+		width += w
+	}
+	return width
+}
+
+// emit is the assembler's second pass: every label is now known, so jump
+// targets and constant indices can be resolved and the final bytecode
+// written out with code.Make.
+func (a *assembler) emit() (*compiler.Bytecode, error) {
+	var out code.Instructions
+
+	for _, ins := range a.instructions {
+		operands := make([]int, len(ins.operands))
+		for i, tok := range ins.operands {
+			n, err := a.resolveOperand(ins.op, tok)
+			if err != nil {
+				return nil, &Error{ins.line, err.Error()}
+			}
+			operands[i] = n
+		}
+		out = append(out, code.Make(ins.op, operands...)...)
+	}
+
+	return &compiler.Bytecode{Instructions: out, Constants: a.constants}, nil
+}
+
+func (a *assembler) resolveOperand(op code.Opcode, tok string) (int, error) {
+	if strings.HasPrefix(tok, ".") {
+		if op != code.OpJump && op != code.OpJumpNotTruthy {
+			return 0, fmt.Errorf("label reference %q not valid here", tok)
+		}
+		name := strings.TrimPrefix(tok, ".")
+		addr, ok := a.labels[name]
+		if !ok {
+			return 0, fmt.Errorf("undefined label %q", tok)
+		}
+		return addr, nil
+	}
+
+	n, err := strconv.Atoi(tok)
+	if err != nil {
+		return 0, fmt.Errorf("invalid operand %q", tok)
+	}
+	return n, nil
+}
+
+// stripComment removes a trailing `; ...` comment, ignoring any `;` that
+// falls inside a double-quoted string literal (so `.string "a;b"` keeps
+// its `;`) and respecting backslash-escaped quotes within it.
+func stripComment(line string) string {
+	inString := false
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			if inString {
+				i++ // skip the escaped character
+			}
+		case '"':
+			inString = !inString
+		case ';':
+			if !inString {
+				return line[:i]
+			}
+		}
+	}
+	return line
+}
+
+func parseQuoted(s string) (string, error) {
+	unquoted, err := strconv.Unquote(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid string literal %q", s)
+	}
+	return unquoted, nil
+}
@@ -0,0 +1,167 @@
+package asm
+
+import (
+	"bytes"
+	"testing"
+
+	"monkey-lang/object"
+	"monkey-lang/vm"
+)
+
+func TestAssembleAndRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		expected int64
+	}{
+		{
+			name: "add two constants",
+			src: `
+.const 3
+.const 4
+OpConstant 0
+OpConstant 1
+OpAdd
+OpPop
+`,
+			expected: 7,
+		},
+		{
+			name: "jump over a constant",
+			src: `
+.const 1
+.const 2
+OpTrue
+OpJumpNotTruthy .alt
+OpConstant 0
+OpJump .done
+.alt:
+OpConstant 1
+.done:
+OpPop
+`,
+			expected: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bc, err := Assemble(tt.src)
+			if err != nil {
+				t.Fatalf("Assemble() error: %s", err)
+			}
+
+			machine := vm.New(bc)
+			if err := machine.Run(); err != nil {
+				t.Fatalf("vm error: %s", err)
+			}
+
+			top := machine.LastPoppedStackElem()
+			integer, ok := top.(*object.Integer)
+			if !ok {
+				t.Fatalf("result is not an Integer. got=%T (%+v)", top, top)
+			}
+			if integer.Value != tt.expected {
+				t.Fatalf("wrong result. want=%d, got=%d", tt.expected, integer.Value)
+			}
+		})
+	}
+}
+
+func TestAssembleUnknownOpcode(t *testing.T) {
+	if _, err := Assemble("OpNotReal"); err == nil {
+		t.Fatalf("expected an error for an unknown opcode")
+	}
+}
+
+func TestAssembleUndefinedLabel(t *testing.T) {
+	if _, err := Assemble("OpJump .nowhere"); err == nil {
+		t.Fatalf("expected an error for a reference to an undefined label")
+	}
+}
+
+func TestAssembleStringConstantContainingSemicolon(t *testing.T) {
+	bc, err := Assemble(`.string "a;b" ; this trailing comment should still be stripped
+OpConstant 0
+`)
+	if err != nil {
+		t.Fatalf("Assemble() error: %s", err)
+	}
+	if len(bc.Constants) != 1 {
+		t.Fatalf("got %d constants, want 1", len(bc.Constants))
+	}
+	if got := bc.Constants[0].Inspect(); got != "a;b" {
+		t.Fatalf("constants[0] = %q, want %q", got, "a;b")
+	}
+}
+
+func TestAssembleLabelNamePrefixingDirective(t *testing.T) {
+	bc, err := Assemble(`
+.constants:
+OpJump .constants
+`)
+	if err != nil {
+		t.Fatalf("Assemble() error: %s", err)
+	}
+	if len(bc.Instructions) == 0 {
+		t.Fatalf("expected the jump to be assembled, got no instructions")
+	}
+
+	if _, err := Assemble(`
+.stringify:
+OpJump .stringify
+`); err != nil {
+		t.Fatalf("Assemble() error: %s", err)
+	}
+}
+
+func TestDisassembleRoundTrip(t *testing.T) {
+	src := ".const 5\n.string \"hi\"\nOpConstant 0\nOpConstant 1\nOpPop\n"
+	bc, err := Assemble(src)
+	if err != nil {
+		t.Fatalf("Assemble() error: %s", err)
+	}
+
+	out := Disassemble(bc)
+	reassembled, err := Assemble(out)
+	if err != nil {
+		t.Fatalf("Assemble(Disassemble(bc)) error: %s\n--- disassembly ---\n%s", err, out)
+	}
+
+	if !bytes.Equal(bc.Instructions, reassembled.Instructions) {
+		t.Fatalf("round trip produced different instructions.\nwant=%v\ngot=%v", bc.Instructions, reassembled.Instructions)
+	}
+
+	if len(reassembled.Constants) != len(bc.Constants) {
+		t.Fatalf("round trip produced %d constants, want %d\n--- disassembly ---\n%s", len(reassembled.Constants), len(bc.Constants), out)
+	}
+	for i, want := range bc.Constants {
+		if got := reassembled.Constants[i]; got.Inspect() != want.Inspect() {
+			t.Fatalf("constants[%d] = %s, want %s", i, got.Inspect(), want.Inspect())
+		}
+	}
+}
+
+func TestSerializeRoundTrip(t *testing.T) {
+	bc, err := Assemble(".const 42\n.string \"hi\"\nOpConstant 0\nOpConstant 1\nOpPop\n")
+	if err != nil {
+		t.Fatalf("Assemble() error: %s", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteBytecode(&buf, bc); err != nil {
+		t.Fatalf("WriteBytecode() error: %s", err)
+	}
+
+	got, err := ReadBytecode(&buf)
+	if err != nil {
+		t.Fatalf("ReadBytecode() error: %s", err)
+	}
+
+	if !bytes.Equal(bc.Instructions, got.Instructions) {
+		t.Fatalf("instructions didn't round trip.\nwant=%v\ngot=%v", bc.Instructions, got.Instructions)
+	}
+	if len(got.Constants) != 2 {
+		t.Fatalf("wrong constant count. want=2, got=%d", len(got.Constants))
+	}
+}
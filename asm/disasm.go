@@ -0,0 +1,126 @@
+package asm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"monkey-lang/code"
+	"monkey-lang/compiler"
+	"monkey-lang/object"
+)
+
+// Disassemble renders bytecode back to the mnemonic text form Assemble
+// reads, with a `.const`/`.string` directive for every entry in
+// bc.Constants (in order, so OpConstant's indices keep meaning the same
+// thing), jump operands resolved to `.label` references, and each
+// OpConstant annotated with the constant it refers to, so the output can
+// be round-tripped through Assemble or just read by a human.
+//
+// Only object.Integer and object.String constants can be represented as
+// directives -- the only literal types .const/.string can produce -- so a
+// constant of any other type (a compiled function, an array, ...) is
+// emitted as a comment instead of a directive and will not survive a
+// round trip through Assemble.
+func Disassemble(bc *compiler.Bytecode) string {
+	labels := labelTargets(bc.Instructions)
+
+	var out strings.Builder
+	for i, c := range bc.Constants {
+		out.WriteString(constantDirective(i, c))
+	}
+	if len(bc.Constants) > 0 {
+		out.WriteByte('\n')
+	}
+
+	offset := 0
+	for offset < len(bc.Instructions) {
+		if name, ok := labels[offset]; ok {
+			fmt.Fprintf(&out, ".%s:\n", name)
+		}
+
+		op := code.Opcode(bc.Instructions[offset])
+		def, err := code.Lookup(op)
+		if err != nil {
+			fmt.Fprintf(&out, "ERROR: %s\n", err)
+			offset++
+			continue
+		}
+
+		operands, width := code.ReadOperands(def, bc.Instructions[offset+1:])
+		out.WriteString(renderInstruction(bc, def, op, operands, labels))
+		out.WriteByte('\n')
+
+		offset += 1 + width
+	}
+	// A jump may target the address just past the last instruction.
+	if name, ok := labels[offset]; ok {
+		fmt.Fprintf(&out, ".%s:\n", name)
+	}
+
+	return out.String()
+}
+
+// constantDirective renders bc.Constants[i] as the .const/.string
+// directive that would produce it at that index, or a plain comment if
+// the constant's type isn't one the assembler can express.
+func constantDirective(i int, c object.Object) string {
+	switch c := c.(type) {
+	case *object.Integer:
+		return fmt.Sprintf(".const %d\n", c.Value)
+	case *object.String:
+		return fmt.Sprintf(".string %s\n", strconv.Quote(c.Value))
+	default:
+		return fmt.Sprintf("; constants[%d] = %s (type %T has no .directive form)\n", i, c.Inspect(), c)
+	}
+}
+
+// labelTargets scans every jump instruction up front and assigns each
+// distinct target address a stable name, ".L<address>", so forward and
+// backward jumps both print symbolically.
+func labelTargets(ins code.Instructions) map[int]string {
+	labels := map[int]string{}
+
+	offset := 0
+	for offset < len(ins) {
+		op := code.Opcode(ins[offset])
+		def, err := code.Lookup(op)
+		if err != nil {
+			offset++
+			continue
+		}
+		operands, width := code.ReadOperands(def, ins[offset+1:])
+		if op == code.OpJump || op == code.OpJumpNotTruthy {
+			target := operands[0]
+			if _, ok := labels[target]; !ok {
+				labels[target] = fmt.Sprintf("L%d", target)
+			}
+		}
+		offset += 1 + width
+	}
+
+	return labels
+}
+
+func renderInstruction(bc *compiler.Bytecode, def *code.Definition, op code.Opcode, operands []int, labels map[int]string) string {
+	switch len(operands) {
+	case 0:
+		return def.Name
+
+	case 1:
+		if (op == code.OpJump || op == code.OpJumpNotTruthy) && labels[operands[0]] != "" {
+			return fmt.Sprintf("%s .%s", def.Name, labels[operands[0]])
+		}
+		if op == code.OpConstant && operands[0] < len(bc.Constants) {
+			return fmt.Sprintf("%s %d ; %s", def.Name, operands[0], bc.Constants[operands[0]].Inspect())
+		}
+		return fmt.Sprintf("%s %d", def.Name, operands[0])
+
+	default:
+		parts := make([]string, len(operands))
+		for i, o := range operands {
+			parts[i] = fmt.Sprintf("%d", o)
+		}
+		return fmt.Sprintf("%s %s", def.Name, strings.Join(parts, " "))
+	}
+}
@@ -0,0 +1,138 @@
+package asm
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"monkey-lang/compiler"
+	"monkey-lang/object"
+)
+
+// mbc is the on-disk format written by `monkey asm` and read by `monkey
+// disasm`/`monkey run`: a magic number, the raw instruction bytes, and the
+// constant pool (currently integers and strings, the only literal types
+// the assembler's .const/.string directives can produce).
+//
+//	magic   uint32
+//	insLen  uint32
+//	ins     []byte
+//	nConsts uint32
+//	consts  repeated { tag byte; ... }
+const mbcMagic uint32 = 0x4d4f4e4b // "MONK"
+
+const (
+	tagInteger byte = iota
+	tagString
+)
+
+// WriteBytecode serializes bc to w in the .mbc format.
+func WriteBytecode(w io.Writer, bc *compiler.Bytecode) error {
+	if err := binary.Write(w, binary.BigEndian, mbcMagic); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.BigEndian, uint32(len(bc.Instructions))); err != nil {
+		return err
+	}
+	if _, err := w.Write(bc.Instructions); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.BigEndian, uint32(len(bc.Constants))); err != nil {
+		return err
+	}
+	for _, c := range bc.Constants {
+		if err := writeConstant(w, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeConstant(w io.Writer, c object.Object) error {
+	switch c := c.(type) {
+	case *object.Integer:
+		if _, err := w.Write([]byte{tagInteger}); err != nil {
+			return err
+		}
+		return binary.Write(w, binary.BigEndian, c.Value)
+
+	case *object.String:
+		if _, err := w.Write([]byte{tagString}); err != nil {
+			return err
+		}
+		b := []byte(c.Value)
+		if err := binary.Write(w, binary.BigEndian, uint32(len(b))); err != nil {
+			return err
+		}
+		_, err := w.Write(b)
+		return err
+
+	default:
+		return fmt.Errorf("asm: cannot serialize constant of type %T", c)
+	}
+}
+
+// ReadBytecode parses the .mbc format written by WriteBytecode.
+func ReadBytecode(r io.Reader) (*compiler.Bytecode, error) {
+	var magic uint32
+	if err := binary.Read(r, binary.BigEndian, &magic); err != nil {
+		return nil, err
+	}
+	if magic != mbcMagic {
+		return nil, fmt.Errorf("asm: not a .mbc file (bad magic %#x)", magic)
+	}
+
+	var insLen uint32
+	if err := binary.Read(r, binary.BigEndian, &insLen); err != nil {
+		return nil, err
+	}
+	ins := make([]byte, insLen)
+	if _, err := io.ReadFull(r, ins); err != nil {
+		return nil, err
+	}
+
+	var nConsts uint32
+	if err := binary.Read(r, binary.BigEndian, &nConsts); err != nil {
+		return nil, err
+	}
+	constants := make([]object.Object, nConsts)
+	for i := range constants {
+		c, err := readConstant(r)
+		if err != nil {
+			return nil, err
+		}
+		constants[i] = c
+	}
+
+	return &compiler.Bytecode{Instructions: ins, Constants: constants}, nil
+}
+
+func readConstant(r io.Reader) (object.Object, error) {
+	var tag [1]byte
+	if _, err := io.ReadFull(r, tag[:]); err != nil {
+		return nil, err
+	}
+
+	switch tag[0] {
+	case tagInteger:
+		var v int64
+		if err := binary.Read(r, binary.BigEndian, &v); err != nil {
+			return nil, err
+		}
+		return &object.Integer{Value: v}, nil
+
+	case tagString:
+		var n uint32
+		if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+			return nil, err
+		}
+		b := make([]byte, n)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		return &object.String{Value: string(b)}, nil
+
+	default:
+		return nil, fmt.Errorf("asm: unknown constant tag %d", tag[0])
+	}
+}
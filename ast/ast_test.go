@@ -0,0 +1,91 @@
+package ast_test
+
+import (
+	"testing"
+
+	"monkey-lang/ast"
+	"monkey-lang/lexer"
+	"monkey-lang/parser"
+)
+
+func parseProgram(t *testing.T, input string) *ast.Program {
+	t.Helper()
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if errs := p.Errors(); len(errs) > 0 {
+		t.Fatalf("parser errors for %q: %v", input, errs)
+	}
+	return program
+}
+
+func TestFoldConstants(t *testing.T) {
+	program := parseProgram(t, "1 + 2 * 3;")
+	ast.FoldConstants(program)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program has %d statements, want 1", len(program.Statements))
+	}
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("statement is not *ast.ExpressionStatement. got=%T", program.Statements[0])
+	}
+	lit, ok := stmt.Expression.(*ast.IntegerLiteral)
+	if !ok {
+		t.Fatalf("1 + 2 * 3 did not fold to a single IntegerLiteral. got=%T (%s)",
+			stmt.Expression, (ast.Printer{}).Print(program))
+	}
+	if lit.Value != 7 {
+		t.Fatalf("folded value = %d, want 7", lit.Value)
+	}
+}
+
+func TestFoldConstantsLeavesVariablesAlone(t *testing.T) {
+	program := parseProgram(t, "x + 2 * 3;")
+	ast.FoldConstants(program)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	infix, ok := stmt.Expression.(*ast.InfixExpression)
+	if !ok {
+		t.Fatalf("expression is not *ast.InfixExpression. got=%T", stmt.Expression)
+	}
+	if _, ok := infix.Left.(*ast.Identifier); !ok {
+		t.Fatalf("left operand was folded away. got=%T", infix.Left)
+	}
+	right, ok := infix.Right.(*ast.IntegerLiteral)
+	if !ok || right.Value != 6 {
+		t.Fatalf("right operand = %#v, want IntegerLiteral{Value: 6}", infix.Right)
+	}
+}
+
+func TestPrinterMinimalParensRoundTrips(t *testing.T) {
+	inputs := []string{
+		"1 + 2 * 3;",
+		"(1 + 2) * 3;",
+		"1 - (2 - 3);",
+		"!(-a);",
+		"if (x < y) { x } else { y };",
+		"fn(x, y) { x + y; };",
+		"add(1, 2 * 3, 4 + 5);",
+	}
+
+	for _, input := range inputs {
+		program := parseProgram(t, input)
+		printed := (ast.Printer{}).Print(program)
+
+		reparsed := parseProgram(t, printed)
+		reprinted := (ast.Printer{}).Print(reparsed)
+
+		if printed != reprinted {
+			t.Errorf("printed form did not round-trip through the parser.\ninput:     %s\nprinted:   %s\nreprinted: %s", input, printed, reprinted)
+		}
+	}
+}
+
+func TestPrinterParensModeFullyParenthesizes(t *testing.T) {
+	program := parseProgram(t, "1 + 2 * 3;")
+	got := (ast.Printer{Parens: true}).Print(program)
+	want := "(1 + (2 * 3));\n"
+	if got != want {
+		t.Errorf("Parens mode output = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,75 @@
+package ast
+
+// FoldConstants rewrites program in place, collapsing integer arithmetic
+// between two literal operands (e.g. `2 * 3`) down to a single
+// IntegerLiteral, repeatedly and bottom-up, so `1 + 2 * 3` folds `2 * 3`
+// to `6` and then `1 + 6` to `7`. It's meant to run once, right before
+// compiler.Compile, so the compiler never has to emit arithmetic on
+// operands that were already known at parse time.
+//
+// Each pass does a single Wrap + post-order Walk to find the first
+// (deepest, leftmost) foldable expression and replaces it with Tree's
+// generic Replace; repeating until a pass finds nothing left to fold
+// keeps every step's tree links trustworthy, at the cost of being
+// O(folds * size) rather than a single linear pass -- fine for the
+// constant subexpressions Monkey programs actually contain.
+func FoldConstants(program *Program) *Program {
+	for {
+		t := Wrap(program)
+		var target, folded Node
+
+		Walk(t, program, VisitorFunc{
+			ExitFn: func(n Node) {
+				if target != nil {
+					return
+				}
+				expr, ok := n.(Expression)
+				if !ok {
+					return
+				}
+				if f := foldExpression(expr); f != nil {
+					target, folded = n, f
+				}
+			},
+		})
+
+		if target == nil {
+			return program
+		}
+		t.Replace(target, folded)
+	}
+}
+
+func foldExpression(expr Expression) Expression {
+	infix, ok := expr.(*InfixExpression)
+	if !ok {
+		return nil
+	}
+	left, ok := infix.Left.(*IntegerLiteral)
+	if !ok {
+		return nil
+	}
+	right, ok := infix.Right.(*IntegerLiteral)
+	if !ok {
+		return nil
+	}
+
+	var value int64
+	switch infix.Operator {
+	case "+":
+		value = left.Value + right.Value
+	case "-":
+		value = left.Value - right.Value
+	case "*":
+		value = left.Value * right.Value
+	case "/":
+		if right.Value == 0 {
+			return nil // let the compiler/VM raise the division-by-zero error
+		}
+		value = left.Value / right.Value
+	default:
+		return nil
+	}
+
+	return &IntegerLiteral{Token: infix.Token, Value: value}
+}
@@ -0,0 +1,139 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+)
+
+// operator precedence, local to the printer: just enough to decide where
+// parens are required when Parens is false. Kept separate from package
+// parser's precedence table to avoid an import cycle (parser depends on
+// this package, not the other way around).
+var infixPrecedence = map[string]int{
+	"==": 1, "!=": 1,
+	"<": 2, ">": 2,
+	"+": 3, "-": 3,
+	"*": 4, "/": 4,
+}
+
+const prefixPrecedence = 5
+
+// Printer renders a Node back to Monkey source text, replacing the node
+// types' ad-hoc String() methods with one place that knows about
+// precedence. With Parens set, every expression is fully parenthesized
+// (matching what the nodes' String() methods produce); without it, only the parens
+// operator precedence actually requires are emitted.
+type Printer struct {
+	Parens bool
+}
+
+// Print renders n. It accepts a *Program, any Statement, or any
+// Expression.
+func (p Printer) Print(n Node) string {
+	switch n := n.(type) {
+	case *Program:
+		var sb strings.Builder
+		for _, stmt := range n.Statements {
+			sb.WriteString(p.printStatement(stmt))
+		}
+		return sb.String()
+	case Statement:
+		return p.printStatement(n)
+	case Expression:
+		return p.printExpr(n, 0)
+	default:
+		return n.TokenLiteral()
+	}
+}
+
+func (p Printer) printStatement(stmt Statement) string {
+	switch s := stmt.(type) {
+	case *LetStatement:
+		return fmt.Sprintf("let %s = %s;\n", s.Name.Value, p.printExpr(s.Value, 0))
+
+	case *ReturnStatement:
+		return fmt.Sprintf("return %s;\n", p.printExpr(s.ReturnValue, 0))
+
+	case *ExpressionStatement:
+		return p.printExpr(s.Expression, 0) + ";\n"
+
+	case *BlockStatement:
+		var sb strings.Builder
+		sb.WriteString("{\n")
+		for _, inner := range s.Statements {
+			sb.WriteString(p.printStatement(inner))
+		}
+		sb.WriteString("}")
+		return sb.String()
+
+	default:
+		return s.TokenLiteral()
+	}
+}
+
+// printExpr renders expr, parenthesizing it if Parens is set or if expr's
+// own precedence is lower than ctx (the precedence of the operator it's
+// sitting under) -- i.e. if leaving the parens out would change what the
+// text parses back to.
+func (p Printer) printExpr(expr Expression, ctx int) string {
+	switch e := expr.(type) {
+	case *Identifier:
+		return e.Value
+
+	case *IntegerLiteral:
+		return fmt.Sprintf("%d", e.Value)
+
+	case *Boolean:
+		return e.Token.Literal
+
+	case *PrefixExpression:
+		s := e.Operator + p.printExpr(e.Right, prefixPrecedence)
+		return p.wrap(s, prefixPrecedence, ctx)
+
+	case *InfixExpression:
+		prec := infixPrecedence[e.Operator]
+		// The right operand is printed as if it needed one more unit of
+		// precedence than the operator provides, so e.g. `1 - (2 - 3)`
+		// keeps its parens even though `-` has equal precedence with
+		// itself -- without it "1 - 2 - 3" would wrongly claim the same
+		// value as the parenthesized form.
+		s := fmt.Sprintf("%s %s %s", p.printExpr(e.Left, prec), e.Operator, p.printExpr(e.Right, prec+1))
+		return p.wrap(s, prec, ctx)
+
+	case *IfExpression:
+		s := fmt.Sprintf("if (%s) %s", p.printExpr(e.Condition, 0), p.printStatement(e.Consequence))
+		if e.Alternative != nil {
+			s += fmt.Sprintf(" else %s", p.printStatement(e.Alternative))
+		}
+		return s
+
+	case *FunctionLiteral:
+		params := make([]string, len(e.Parameters))
+		for i, ident := range e.Parameters {
+			params[i] = ident.Value
+		}
+		return fmt.Sprintf("fn(%s) %s", strings.Join(params, ", "), p.printStatement(e.Body))
+
+	case *CallExpression:
+		args := make([]string, len(e.Arguments))
+		for i, arg := range e.Arguments {
+			args[i] = p.printExpr(arg, 0)
+		}
+		return fmt.Sprintf("%s(%s)", p.printExpr(e.Function, CALL), strings.Join(args, ", "))
+
+	default:
+		return e.TokenLiteral()
+	}
+}
+
+// CALL is higher than every infix operator, so a function expression that
+// is itself e.g. an InfixExpression gets parenthesized before being
+// called, matching how the parser requires `(a + b)(1)` to be written.
+const CALL = prefixPrecedence + 1
+
+func (p Printer) wrap(s string, own, ctx int) string {
+	if p.Parens || own < ctx {
+		return "(" + s + ")"
+	}
+	return s
+}
@@ -0,0 +1,279 @@
+package ast
+
+import "reflect"
+
+// Tree is a doubly-linked side table over an *ast.Program, built by Wrap.
+// It doesn't change the node structs themselves -- Node, Expression, and
+// Statement stay exactly as the parser produces them -- it just layers
+// Parent/PrevSibling/NextSibling/FirstChild lookups and structural edits
+// (Replace, Remove) on top, keyed by node identity.
+type Tree struct {
+	root  Node
+	links map[Node]*nodeLinks
+}
+
+type nodeLinks struct {
+	parent, prev, next, firstChild Node
+}
+
+// Wrap walks program once and records every node's parent and siblings.
+// Call it again (or call a Tree method that does so internally, as Replace
+// and Remove do) after editing the tree, since edits can move a node to a
+// different parent or a different position among its siblings.
+func Wrap(program *Program) *Tree {
+	t := &Tree{root: program}
+	t.rebuild()
+	return t
+}
+
+func (t *Tree) rebuild() {
+	t.links = make(map[Node]*nodeLinks)
+	t.link(nil, t.root)
+}
+
+// link records n's parent and, if n has siblings under that parent,
+// threads them together, then recurses into n's children.
+func (t *Tree) link(parent Node, n Node) {
+	if isNilNode(n) {
+		return
+	}
+	t.links[n] = &nodeLinks{parent: parent}
+
+	kids := children(n)
+	var prev Node
+	for _, kid := range kids {
+		if isNilNode(kid) {
+			continue
+		}
+		t.link(n, kid)
+		if prev != nil {
+			t.links[prev].next = kid
+			t.links[kid].prev = prev
+		} else {
+			t.links[n].firstChild = kid
+		}
+		prev = kid
+	}
+}
+
+// Parent returns n's parent, or nil if n is the root or unknown to t.
+func (t *Tree) Parent(n Node) Node { return t.get(n).parent }
+
+// PrevSibling returns the child immediately before n under its parent.
+func (t *Tree) PrevSibling(n Node) Node { return t.get(n).prev }
+
+// NextSibling returns the child immediately after n under its parent.
+func (t *Tree) NextSibling(n Node) Node { return t.get(n).next }
+
+// FirstChild returns n's first child, or nil if n is a leaf.
+func (t *Tree) FirstChild(n Node) Node { return t.get(n).firstChild }
+
+func (t *Tree) get(n Node) *nodeLinks {
+	if l, ok := t.links[n]; ok {
+		return l
+	}
+	return &nodeLinks{}
+}
+
+// Visitor receives pre-order (Enter) and post-order (Exit) callbacks as
+// Walk descends the tree. Enter returning false skips n's children (and
+// the matching Exit call).
+type Visitor interface {
+	Enter(n Node) bool
+	Exit(n Node)
+}
+
+// VisitorFunc adapts a pair of functions to the Visitor interface for
+// passes that only care about one direction.
+type VisitorFunc struct {
+	EnterFn func(Node) bool
+	ExitFn  func(Node)
+}
+
+func (v VisitorFunc) Enter(n Node) bool {
+	if v.EnterFn == nil {
+		return true
+	}
+	return v.EnterFn(n)
+}
+
+func (v VisitorFunc) Exit(n Node) {
+	if v.ExitFn != nil {
+		v.ExitFn(n)
+	}
+}
+
+// Walk traverses n and its descendants (via Tree's links, so it reflects
+// any prior Replace/Remove edits), calling v.Enter before and v.Exit after
+// visiting each node's children.
+func Walk(t *Tree, n Node, v Visitor) {
+	if isNilNode(n) {
+		return
+	}
+	if !v.Enter(n) {
+		return
+	}
+	for kid := t.FirstChild(n); !isNilNode(kid); kid = t.NextSibling(kid) {
+		Walk(t, kid, v)
+	}
+	v.Exit(n)
+}
+
+// Replace swaps old for new wherever old is referenced from its parent --
+// a single field, or one element of a slice of statements/expressions --
+// and rebuilds the tree's links so Parent/siblings are consistent with the
+// edit. If old is the tree's root, new becomes the root. It is a no-op if
+// old isn't part of this tree.
+func (t *Tree) Replace(old, new Node) {
+	parent := t.Parent(old)
+	if isNilNode(parent) {
+		if t.root == old {
+			t.root = new
+			t.rebuild()
+		}
+		return
+	}
+	if setChild(parent, old, new) {
+		t.rebuild()
+	}
+}
+
+// Remove deletes old from its parent: from a slice field it drops the
+// element, from a scalar field it clears it to nil. It rebuilds the
+// tree's links afterward. It is a no-op if old's parent can't be found.
+func (t *Tree) Remove(old Node) {
+	parent := t.Parent(old)
+	if isNilNode(parent) {
+		return
+	}
+	if removeChild(parent, old) {
+		t.rebuild()
+	}
+}
+
+// children enumerates n's direct Node-valued fields -- single Node/
+// Expression/Statement fields and slices of them -- via reflection, so
+// Wrap/Walk work over any node type without this package hardcoding each
+// one's shape.
+func children(n Node) []Node {
+	if isNilNode(n) {
+		return nil
+	}
+	v := reflect.ValueOf(n)
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var out []Node
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		switch f.Kind() {
+		case reflect.Slice:
+			for j := 0; j < f.Len(); j++ {
+				if node, ok := asNode(f.Index(j)); ok {
+					out = append(out, node)
+				}
+			}
+		case reflect.Interface, reflect.Ptr:
+			if node, ok := asNode(f); ok {
+				out = append(out, node)
+			}
+		}
+	}
+	return out
+}
+
+func asNode(v reflect.Value) (Node, bool) {
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		return nil, false
+	}
+	if !v.CanInterface() {
+		return nil, false
+	}
+	node, ok := v.Interface().(Node)
+	if !ok || isNilNode(node) {
+		return nil, false
+	}
+	return node, true
+}
+
+func isNilNode(n Node) bool {
+	if n == nil {
+		return true
+	}
+	v := reflect.ValueOf(n)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map, reflect.Chan, reflect.Func:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
+
+// setChild finds the field (or slice element) of parent holding old and
+// points it at new instead. It reports whether it found and replaced one.
+func setChild(parent, old, new Node) bool {
+	v := reflect.ValueOf(parent)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return false
+	}
+
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		switch f.Kind() {
+		case reflect.Slice:
+			for j := 0; j < f.Len(); j++ {
+				if node, ok := asNode(f.Index(j)); ok && node == old {
+					f.Index(j).Set(reflect.ValueOf(new))
+					return true
+				}
+			}
+		case reflect.Interface, reflect.Ptr:
+			if node, ok := asNode(f); ok && node == old && f.CanSet() {
+				f.Set(reflect.ValueOf(new))
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// removeChild finds the field (or slice element) of parent holding old and
+// clears it -- shrinking the slice, or zeroing a scalar field.
+func removeChild(parent, old Node) bool {
+	v := reflect.ValueOf(parent)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return false
+	}
+
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		switch f.Kind() {
+		case reflect.Slice:
+			for j := 0; j < f.Len(); j++ {
+				if node, ok := asNode(f.Index(j)); ok && node == old {
+					f.Set(reflect.AppendSlice(f.Slice(0, j), f.Slice(j+1, f.Len())))
+					return true
+				}
+			}
+		case reflect.Interface, reflect.Ptr:
+			if node, ok := asNode(f); ok && node == old && f.CanSet() {
+				f.Set(reflect.Zero(f.Type()))
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -1,14 +1,145 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 
-	"github.com/NavrajBal/monkey-lang/repl"
+	"monkey-lang/asm"
+	"monkey-lang/repl"
+	"monkey-lang/vm"
 )
 
-// main starts a simple REPL that prints tokens for each input line
+// main dispatches to a subcommand (asm, disasm, run) when one is given as
+// the first argument, and otherwise starts the REPL.
 func main() {
-	fmt.Println("Monkey programming language! Start typing...")
-	repl.Start(os.Stdin, os.Stdout)
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "asm":
+			os.Exit(runAsm(os.Args[2:]))
+		case "disasm":
+			os.Exit(runDisasm(os.Args[2:]))
+		case "run":
+			os.Exit(runBytecode(os.Args[2:]))
+		}
+	}
+
+	startREPL(os.Args[1:])
+}
+
+func startREPL(args []string) {
+	fs := flag.NewFlagSet("monkey", flag.ExitOnError)
+	mode := fs.String("mode", string(repl.ModeCompile),
+		"execution engine for the REPL: tokens, ast, eval, or compile")
+	fs.Parse(args)
+
+	cfg := repl.Config{Mode: repl.Mode(*mode)}
+
+	fmt.Printf("Monkey programming language! Start typing... (mode: %s, :help for commands)\n", cfg.Mode)
+	repl.Start(os.Stdin, os.Stdout, cfg)
+}
+
+// runAsm implements `monkey asm file.easm -o out.mbc`: assemble a text
+// program into the binary .mbc bytecode format.
+func runAsm(args []string) int {
+	fs := flag.NewFlagSet("asm", flag.ExitOnError)
+	out := fs.String("o", "", "output .mbc file (required)")
+	fs.Parse(args)
+
+	// The flag package stops at the first non-flag argument, so parse what
+	// follows the input file again to pick up a trailing -o.
+	var files []string
+	for fs.NArg() > 0 {
+		files = append(files, fs.Arg(0))
+		fs.Parse(fs.Args()[1:])
+	}
+
+	if len(files) != 1 || *out == "" {
+		fmt.Fprintln(os.Stderr, "usage: monkey asm <file.easm> -o <out.mbc>")
+		return 2
+	}
+
+	src, err := os.ReadFile(files[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "asm: %s\n", err)
+		return 1
+	}
+
+	bc, err := asm.Assemble(string(src))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "asm: %s\n", err)
+		return 1
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "asm: %s\n", err)
+		return 1
+	}
+	defer f.Close()
+
+	if err := asm.WriteBytecode(f, bc); err != nil {
+		fmt.Fprintf(os.Stderr, "asm: %s\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+// runDisasm implements `monkey disasm out.mbc`: print the mnemonic text
+// form of a previously assembled or compiled bytecode file.
+func runDisasm(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "usage: monkey disasm <file.mbc>")
+		return 2
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "disasm: %s\n", err)
+		return 1
+	}
+	defer f.Close()
+
+	bc, err := asm.ReadBytecode(f)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "disasm: %s\n", err)
+		return 1
+	}
+
+	fmt.Print(asm.Disassemble(bc))
+	return 0
+}
+
+// runBytecode implements `monkey run out.mbc`: load a .mbc file and
+// execute it on the VM directly, skipping lexing/parsing/compiling.
+func runBytecode(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "usage: monkey run <file.mbc>")
+		return 2
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "run: %s\n", err)
+		return 1
+	}
+	defer f.Close()
+
+	bc, err := asm.ReadBytecode(f)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "run: %s\n", err)
+		return 1
+	}
+
+	machine := vm.New(bc)
+	if err := machine.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "run: %s\n", err)
+		return 1
+	}
+
+	if top := machine.LastPoppedStackElem(); top != nil {
+		fmt.Println(top.Inspect())
+	}
+	return 0
 }
@@ -0,0 +1,100 @@
+package parser
+
+import (
+	"monkey-lang/lexer"
+	"monkey-lang/token"
+)
+
+// Marker is an opaque bookmark into a cursor's token stream, returned by
+// Marker() and consumed by Reset(). It lets speculative parsing take a
+// production, and if it turns out to be the wrong one, rewind and try a
+// different one instead -- e.g. telling `fn(x, y) { ... }` apart from a
+// parenthesized expression before committing to either.
+type Marker struct {
+	pos int
+}
+
+// Parser is the lookahead/backtracking surface the Pratt parser in this
+// package is built on. It's exported so third parties writing Monkey
+// macros or embedded DSLs can drive their own productions over the same
+// token stream primitives, without depending on this package's grammar.
+type Parser interface {
+	// PeekToken returns the token n positions ahead of the current one.
+	// PeekToken(0) is the current token, PeekToken(1) is the next one.
+	PeekToken(n int) token.Token
+	// PeekTokenType is a convenience wrapper around PeekToken(n).Type.
+	PeekTokenType(n int) token.TokenType
+	// NextToken advances the cursor by one token and returns it as the
+	// new current token.
+	NextToken() token.Token
+	// BackupToken rewinds the cursor by one token; equivalent to
+	// BackupTokens(1).
+	BackupToken()
+	// BackupTokens rewinds the cursor by n tokens.
+	BackupTokens(n int)
+	// Marker captures the current position for a later Reset.
+	Marker() *Marker
+	// Reset rewinds the cursor to the position captured by m.
+	Reset(m *Marker)
+}
+
+// cursor is a lookahead/backup buffer over a lexer. Every token the lexer
+// produces is cached the first time it's requested, so arbitrary
+// PeekToken(n) and BackupTokens(n) calls never re-lex -- they just move an
+// index into the buffer, giving O(1) amortized lookahead and backup.
+type cursor struct {
+	l   *lexer.Lexer
+	buf []token.Token
+	pos int // index into buf of the current token; -1 before the first NextToken
+}
+
+var _ Parser = (*cursor)(nil)
+
+func newCursor(l *lexer.Lexer) *cursor {
+	return &cursor{l: l, pos: -1}
+}
+
+// fill grows buf, lexing new tokens, until buf[idx] is populated.
+func (c *cursor) fill(idx int) {
+	for len(c.buf) <= idx {
+		c.buf = append(c.buf, c.l.NextToken())
+	}
+}
+
+func (c *cursor) PeekToken(n int) token.Token {
+	idx := c.pos + n
+	if idx < 0 {
+		return token.Token{}
+	}
+	c.fill(idx)
+	return c.buf[idx]
+}
+
+func (c *cursor) PeekTokenType(n int) token.TokenType {
+	return c.PeekToken(n).Type
+}
+
+func (c *cursor) NextToken() token.Token {
+	c.pos++
+	c.fill(c.pos)
+	return c.buf[c.pos]
+}
+
+func (c *cursor) BackupToken() {
+	c.BackupTokens(1)
+}
+
+func (c *cursor) BackupTokens(n int) {
+	c.pos -= n
+	if c.pos < -1 {
+		c.pos = -1
+	}
+}
+
+func (c *cursor) Marker() *Marker {
+	return &Marker{pos: c.pos}
+}
+
+func (c *cursor) Reset(m *Marker) {
+	c.pos = m.pos
+}
@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"testing"
+
+	"monkey-lang/lexer"
+	"monkey-lang/token"
+)
+
+func TestCursorPeekDoesNotConsume(t *testing.T) {
+	c := newCursor(lexer.New("+ - *"))
+
+	if tt := c.PeekToken(1).Type; tt != token.PLUS {
+		t.Fatalf("PeekToken(1) = %s, want %s", tt, token.PLUS)
+	}
+	if tt := c.PeekToken(2).Type; tt != token.MINUS {
+		t.Fatalf("PeekToken(2) = %s, want %s", tt, token.MINUS)
+	}
+	// Peeking ahead must not have advanced the cursor.
+	if tt := c.NextToken().Type; tt != token.PLUS {
+		t.Fatalf("NextToken() = %s, want %s", tt, token.PLUS)
+	}
+}
+
+func TestCursorMarkerResetRewinds(t *testing.T) {
+	c := newCursor(lexer.New("+ - * /"))
+
+	c.NextToken() // +
+	mark := c.Marker()
+
+	c.NextToken() // -
+	c.NextToken() // *
+	if tt := c.PeekToken(0).Type; tt != token.ASTERISK {
+		t.Fatalf("PeekToken(0) before reset = %s, want %s", tt, token.ASTERISK)
+	}
+
+	c.Reset(mark)
+	if tt := c.PeekToken(0).Type; tt != token.PLUS {
+		t.Fatalf("PeekToken(0) after reset = %s, want %s", tt, token.PLUS)
+	}
+	// Replaying from the marker must reproduce the same tokens, proving
+	// the buffer -- not the lexer -- served them the second time.
+	if tt := c.NextToken().Type; tt != token.MINUS {
+		t.Fatalf("NextToken() after reset = %s, want %s", tt, token.MINUS)
+	}
+	if tt := c.NextToken().Type; tt != token.ASTERISK {
+		t.Fatalf("NextToken() after reset = %s, want %s", tt, token.ASTERISK)
+	}
+}
+
+func TestCursorBackupTokens(t *testing.T) {
+	c := newCursor(lexer.New("+ - * /"))
+
+	c.NextToken() // +
+	c.NextToken() // -
+	c.NextToken() // *
+
+	c.BackupTokens(2)
+	if tt := c.PeekToken(0).Type; tt != token.PLUS {
+		t.Fatalf("PeekToken(0) after BackupTokens(2) = %s, want %s", tt, token.PLUS)
+	}
+}
@@ -0,0 +1,415 @@
+// Package parser turns a token stream into an *ast.Program using Pratt
+// (operator precedence) parsing. The token stream itself is handled by
+// cursor, which implements the Parser interface with arbitrary lookahead
+// and marker-based backup -- see cursor.go -- so productions can try a
+// tentative parse and roll back if it doesn't hold up, rather than needing
+// a fixed one- or two-token lookahead baked into the grammar.
+package parser
+
+import (
+	"fmt"
+	"strconv"
+
+	"monkey-lang/ast"
+	"monkey-lang/lexer"
+	"monkey-lang/token"
+)
+
+const (
+	_ int = iota
+	LOWEST
+	EQUALS      // ==
+	LESSGREATER // > or <
+	SUM         // +
+	PRODUCT     // *
+	PREFIX      // -X or !X
+	CALL        // myFunction(X)
+)
+
+var precedences = map[token.TokenType]int{
+	token.EQ:       EQUALS,
+	token.NOT_EQ:   EQUALS,
+	token.LT:       LESSGREATER,
+	token.GT:       LESSGREATER,
+	token.PLUS:     SUM,
+	token.MINUS:    SUM,
+	token.SLASH:    PRODUCT,
+	token.ASTERISK: PRODUCT,
+	token.LPAREN:   CALL,
+}
+
+type (
+	prefixParseFn func() ast.Expression
+	infixParseFn  func(ast.Expression) ast.Expression
+)
+
+// PrattParser is this package's concrete Parser: it implements the Parser
+// interface (by embedding *cursor) for anyone who wants to drive the token
+// stream directly, and layers a standard Pratt expression parser plus
+// statement grammar on top of it.
+type PrattParser struct {
+	*cursor
+
+	errors []string
+
+	prefixParseFns map[token.TokenType]prefixParseFn
+	infixParseFns  map[token.TokenType]infixParseFn
+}
+
+// New creates a PrattParser reading tokens from l.
+func New(l *lexer.Lexer) *PrattParser {
+	p := &PrattParser{cursor: newCursor(l)}
+
+	p.prefixParseFns = make(map[token.TokenType]prefixParseFn)
+	p.registerPrefix(token.IDENT, p.parseIdentifier)
+	p.registerPrefix(token.INT, p.parseIntegerLiteral)
+	p.registerPrefix(token.BANG, p.parsePrefixExpression)
+	p.registerPrefix(token.MINUS, p.parsePrefixExpression)
+	p.registerPrefix(token.TRUE, p.parseBoolean)
+	p.registerPrefix(token.FALSE, p.parseBoolean)
+	p.registerPrefix(token.LPAREN, p.parseGroupedExpression)
+	p.registerPrefix(token.IF, p.parseIfExpression)
+	p.registerPrefix(token.FUNCTION, p.parseFunctionLiteral)
+
+	p.infixParseFns = make(map[token.TokenType]infixParseFn)
+	for _, tt := range []token.TokenType{token.PLUS, token.MINUS, token.SLASH, token.ASTERISK, token.EQ, token.NOT_EQ, token.LT, token.GT} {
+		p.registerInfix(tt, p.parseInfixExpression)
+	}
+	p.registerInfix(token.LPAREN, p.parseCallExpression)
+
+	// Load the first token as curToken (PeekToken(0)); peekToken
+	// (PeekToken(1)) is lexed on demand by the cursor.
+	p.NextToken()
+
+	return p
+}
+
+func (p *PrattParser) registerPrefix(tt token.TokenType, fn prefixParseFn) {
+	p.prefixParseFns[tt] = fn
+}
+
+func (p *PrattParser) registerInfix(tt token.TokenType, fn infixParseFn) {
+	p.infixParseFns[tt] = fn
+}
+
+// Errors returns the parse errors accumulated so far.
+func (p *PrattParser) Errors() []string {
+	return p.errors
+}
+
+func (p *PrattParser) curToken() token.Token  { return p.PeekToken(0) }
+func (p *PrattParser) peekToken() token.Token { return p.PeekToken(1) }
+
+func (p *PrattParser) curTokenIs(tt token.TokenType) bool  { return p.curToken().Type == tt }
+func (p *PrattParser) peekTokenIs(tt token.TokenType) bool { return p.peekToken().Type == tt }
+
+func (p *PrattParser) expectPeek(tt token.TokenType) bool {
+	if p.peekTokenIs(tt) {
+		p.NextToken()
+		return true
+	}
+	p.peekError(tt)
+	return false
+}
+
+func (p *PrattParser) peekError(tt token.TokenType) {
+	msg := fmt.Sprintf("line %d:%d: expected next token to be %s, got %s instead",
+		p.curToken().Line, p.curToken().Column, tt, p.peekToken().Type)
+	p.errors = append(p.errors, msg)
+}
+
+func (p *PrattParser) noPrefixParseFnError(tt token.TokenType) {
+	msg := fmt.Sprintf("line %d:%d: no prefix parse function for %s found",
+		p.curToken().Line, p.curToken().Column, tt)
+	p.errors = append(p.errors, msg)
+}
+
+func (p *PrattParser) peekPrecedence() int {
+	if pr, ok := precedences[p.peekToken().Type]; ok {
+		return pr
+	}
+	return LOWEST
+}
+
+func (p *PrattParser) curPrecedence() int {
+	if pr, ok := precedences[p.curToken().Type]; ok {
+		return pr
+	}
+	return LOWEST
+}
+
+// ParseProgram parses the whole token stream into an *ast.Program.
+func (p *PrattParser) ParseProgram() *ast.Program {
+	program := &ast.Program{Statements: []ast.Statement{}}
+
+	for !p.curTokenIs(token.EOF) {
+		if stmt := p.parseStatement(); stmt != nil {
+			program.Statements = append(program.Statements, stmt)
+		}
+		p.NextToken()
+	}
+
+	return program
+}
+
+func (p *PrattParser) parseStatement() ast.Statement {
+	switch p.curToken().Type {
+	case token.LET:
+		return p.parseLetStatement()
+	case token.RETURN:
+		return p.parseReturnStatement()
+	default:
+		return p.parseExpressionStatement()
+	}
+}
+
+func (p *PrattParser) parseLetStatement() ast.Statement {
+	stmt := &ast.LetStatement{Token: p.curToken()}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	stmt.Name = &ast.Identifier{Token: p.curToken(), Value: p.curToken().Literal}
+
+	if !p.expectPeek(token.ASSIGN) {
+		return nil
+	}
+	p.NextToken()
+
+	stmt.Value = p.parseExpression(LOWEST)
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.NextToken()
+	}
+
+	return stmt
+}
+
+func (p *PrattParser) parseReturnStatement() ast.Statement {
+	stmt := &ast.ReturnStatement{Token: p.curToken()}
+
+	p.NextToken()
+
+	stmt.ReturnValue = p.parseExpression(LOWEST)
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.NextToken()
+	}
+
+	return stmt
+}
+
+func (p *PrattParser) parseExpressionStatement() ast.Statement {
+	stmt := &ast.ExpressionStatement{Token: p.curToken()}
+
+	stmt.Expression = p.parseExpression(LOWEST)
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.NextToken()
+	}
+
+	return stmt
+}
+
+func (p *PrattParser) parseExpression(precedence int) ast.Expression {
+	prefix := p.prefixParseFns[p.curToken().Type]
+	if prefix == nil {
+		p.noPrefixParseFnError(p.curToken().Type)
+		return nil
+	}
+	leftExp := prefix()
+
+	for !p.peekTokenIs(token.SEMICOLON) && precedence < p.peekPrecedence() {
+		infix := p.infixParseFns[p.peekToken().Type]
+		if infix == nil {
+			return leftExp
+		}
+		p.NextToken()
+		leftExp = infix(leftExp)
+	}
+
+	return leftExp
+}
+
+func (p *PrattParser) parseIdentifier() ast.Expression {
+	return &ast.Identifier{Token: p.curToken(), Value: p.curToken().Literal}
+}
+
+func (p *PrattParser) parseIntegerLiteral() ast.Expression {
+	lit := &ast.IntegerLiteral{Token: p.curToken()}
+
+	value, err := strconv.ParseInt(p.curToken().Literal, 0, 64)
+	if err != nil {
+		msg := fmt.Sprintf("line %d:%d: could not parse %q as integer",
+			p.curToken().Line, p.curToken().Column, p.curToken().Literal)
+		p.errors = append(p.errors, msg)
+		return nil
+	}
+
+	lit.Value = value
+	return lit
+}
+
+func (p *PrattParser) parseBoolean() ast.Expression {
+	return &ast.Boolean{Token: p.curToken(), Value: p.curTokenIs(token.TRUE)}
+}
+
+func (p *PrattParser) parsePrefixExpression() ast.Expression {
+	expr := &ast.PrefixExpression{Token: p.curToken(), Operator: p.curToken().Literal}
+	p.NextToken()
+	expr.Right = p.parseExpression(PREFIX)
+	return expr
+}
+
+func (p *PrattParser) parseInfixExpression(left ast.Expression) ast.Expression {
+	expr := &ast.InfixExpression{Token: p.curToken(), Operator: p.curToken().Literal, Left: left}
+	precedence := p.curPrecedence()
+	p.NextToken()
+	expr.Right = p.parseExpression(precedence)
+	return expr
+}
+
+// parseGroupedExpression parses a parenthesized expression, `(1 + 2)`. It
+// takes a Marker before attempting the parse and rewinds to it (including
+// discarding any speculative parse errors the attempt recorded) if the
+// group never closes with a clean RPAREN, so the cursor and error list are
+// left exactly as they were found rather than partially advanced. Nothing
+// in this grammar currently retries a different production from that
+// marker -- parseFunctionLiteral parses its own `(x, y)` parameter list
+// directly, it never falls through here -- but the rollback is what a
+// future production sharing this LPAREN prefix (e.g. tuple/destructuring
+// `let (a, b) = ...`) would need to try a parse and fall back cleanly.
+func (p *PrattParser) parseGroupedExpression() ast.Expression {
+	mark := p.Marker()
+	errMark := len(p.errors)
+
+	p.NextToken()
+	exp := p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPAREN) {
+		p.errors = p.errors[:errMark]
+		p.Reset(mark)
+		return nil
+	}
+
+	return exp
+}
+
+func (p *PrattParser) parseIfExpression() ast.Expression {
+	expr := &ast.IfExpression{Token: p.curToken()}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	p.NextToken()
+	expr.Condition = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	expr.Consequence = p.parseBlockStatement()
+
+	if p.peekTokenIs(token.ELSE) {
+		p.NextToken()
+
+		if !p.expectPeek(token.LBRACE) {
+			return nil
+		}
+
+		expr.Alternative = p.parseBlockStatement()
+	}
+
+	return expr
+}
+
+func (p *PrattParser) parseBlockStatement() *ast.BlockStatement {
+	block := &ast.BlockStatement{Token: p.curToken(), Statements: []ast.Statement{}}
+
+	p.NextToken()
+
+	for !p.curTokenIs(token.RBRACE) && !p.curTokenIs(token.EOF) {
+		if stmt := p.parseStatement(); stmt != nil {
+			block.Statements = append(block.Statements, stmt)
+		}
+		p.NextToken()
+	}
+
+	return block
+}
+
+func (p *PrattParser) parseFunctionLiteral() ast.Expression {
+	lit := &ast.FunctionLiteral{Token: p.curToken()}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	lit.Parameters = p.parseFunctionParameters()
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	lit.Body = p.parseBlockStatement()
+
+	return lit
+}
+
+func (p *PrattParser) parseFunctionParameters() []*ast.Identifier {
+	identifiers := []*ast.Identifier{}
+
+	if p.peekTokenIs(token.RPAREN) {
+		p.NextToken()
+		return identifiers
+	}
+
+	p.NextToken()
+	identifiers = append(identifiers, &ast.Identifier{Token: p.curToken(), Value: p.curToken().Literal})
+
+	for p.peekTokenIs(token.COMMA) {
+		p.NextToken()
+		p.NextToken()
+		identifiers = append(identifiers, &ast.Identifier{Token: p.curToken(), Value: p.curToken().Literal})
+	}
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	return identifiers
+}
+
+func (p *PrattParser) parseCallExpression(function ast.Expression) ast.Expression {
+	expr := &ast.CallExpression{Token: p.curToken(), Function: function}
+	expr.Arguments = p.parseCallArguments()
+	return expr
+}
+
+func (p *PrattParser) parseCallArguments() []ast.Expression {
+	args := []ast.Expression{}
+
+	if p.peekTokenIs(token.RPAREN) {
+		p.NextToken()
+		return args
+	}
+
+	p.NextToken()
+	args = append(args, p.parseExpression(LOWEST))
+
+	for p.peekTokenIs(token.COMMA) {
+		p.NextToken()
+		p.NextToken()
+		args = append(args, p.parseExpression(LOWEST))
+	}
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	return args
+}
@@ -0,0 +1,144 @@
+package parser
+
+import (
+	"fmt"
+	"testing"
+
+	"monkey-lang/ast"
+	"monkey-lang/lexer"
+)
+
+func checkParserErrors(t *testing.T, p *PrattParser) {
+	t.Helper()
+	errs := p.Errors()
+	if len(errs) == 0 {
+		return
+	}
+	t.Errorf("parser had %d errors", len(errs))
+	for _, msg := range errs {
+		t.Errorf("parser error: %s", msg)
+	}
+}
+
+func TestLetStatements(t *testing.T) {
+	input := `
+let x = 5;
+let y = 10;
+let foobar = 838383;
+`
+	program := New(lexer.New(input)).ParseProgram()
+	if len(program.Statements) != 3 {
+		t.Fatalf("program.Statements does not contain 3 statements. got=%d", len(program.Statements))
+	}
+
+	expected := []string{"x", "y", "foobar"}
+	for i, name := range expected {
+		stmt := program.Statements[i]
+		letStmt, ok := stmt.(*ast.LetStatement)
+		if !ok {
+			t.Fatalf("statement %d is not *ast.LetStatement. got=%T", i, stmt)
+		}
+		if letStmt.Name.Value != name {
+			t.Fatalf("letStmt.Name.Value = %s, want %s", letStmt.Name.Value, name)
+		}
+	}
+}
+
+func TestOperatorPrecedence(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1 + 2 * 3", "(1 + (2 * 3))"},
+		{"(1 + 2) * 3", "((1 + 2) * 3)"},
+		{"-1 * 2", "((-1) * 2)"},
+		{"1 + 2 + 3", "((1 + 2) + 3)"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		got := (ast.Printer{Parens: true}).Print(program.Statements[0].(*ast.ExpressionStatement).Expression)
+		if got != tt.expected {
+			t.Errorf("input %q: got %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+// TestFunctionLiteralParameters is a plain parse check for `fn(x, y) {
+// ... }`. Note this does NOT go through parseGroupedExpression's
+// Marker/Reset rollback: parseFunctionLiteral parses its own `(x, y)`
+// parameter list directly once it sees the `fn` keyword, so there's no
+// ambiguity here for the grammar to backtrack out of. See
+// TestParseGroupedExpressionResetsOnFailure below for a test of the
+// rollback itself.
+func TestFunctionLiteralParameters(t *testing.T) {
+	input := `fn(x, y) { x + y; }`
+
+	p := New(lexer.New(input))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements has %d statements, want 1", len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("statement is not *ast.ExpressionStatement. got=%T", program.Statements[0])
+	}
+
+	fn, ok := stmt.Expression.(*ast.FunctionLiteral)
+	if !ok {
+		t.Fatalf("expression is not *ast.FunctionLiteral. got=%T", stmt.Expression)
+	}
+	if len(fn.Parameters) != 2 {
+		t.Fatalf("function literal has %d parameters, want 2", len(fn.Parameters))
+	}
+	if fn.Parameters[0].Value != "x" || fn.Parameters[1].Value != "y" {
+		t.Fatalf("unexpected parameters: %v", fn.Parameters)
+	}
+}
+
+// TestParseGroupedExpressionResetsOnFailure exercises the actual
+// Marker/Reset rollback in parseGroupedExpression: an unterminated group
+// should leave both the cursor and the error list exactly as they were
+// before the speculative attempt, rather than stranding the cursor
+// mid-group or leaking a "expected RPAREN" error that the caller never
+// asked for.
+func TestParseGroupedExpressionResetsOnFailure(t *testing.T) {
+	p := New(lexer.New("(1 + 2 true"))
+	mark := p.Marker()
+
+	if expr := p.parseGroupedExpression(); expr != nil {
+		t.Fatalf("parseGroupedExpression() = %v, want nil for an unterminated group", expr)
+	}
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parseGroupedExpression() left %d speculative error(s) behind: %v", len(p.Errors()), p.Errors())
+	}
+	if reset := p.Marker(); reset.pos != mark.pos {
+		t.Fatalf("cursor position = %d after rollback, want %d (where it started)", reset.pos, mark.pos)
+	}
+}
+
+func TestCallExpressionParsing(t *testing.T) {
+	input := "add(1, 2 * 3, 4 + 5);"
+
+	p := New(lexer.New(input))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	call, ok := stmt.Expression.(*ast.CallExpression)
+	if !ok {
+		t.Fatalf("expression is not *ast.CallExpression. got=%T", stmt.Expression)
+	}
+	if len(call.Arguments) != 3 {
+		t.Fatalf("call has %d arguments, want 3", len(call.Arguments))
+	}
+	if got := fmt.Sprintf("%s", call.Arguments[1].String()); got != "(2 * 3)" {
+		t.Fatalf("call.Arguments[1] = %q, want %q", got, "(2 * 3)")
+	}
+}
@@ -0,0 +1,61 @@
+package repl
+
+// history is a small ring buffer of previously submitted lines, used to
+// support up/down recall in the REPL's line reader. It grows up to
+// historyLimit entries and then overwrites the oldest ones.
+type history struct {
+	entries []string
+	cursor  int // index into entries while recalling; len(entries) means "not recalling"
+}
+
+const historyLimit = 1000
+
+func newHistory() *history {
+	return &history{entries: make([]string, 0, 64), cursor: 0}
+}
+
+// Add appends a submitted line to the history and resets the recall cursor.
+// Blank lines and immediate repeats of the last entry are not recorded.
+func (h *history) Add(line string) {
+	if line == "" {
+		return
+	}
+	if n := len(h.entries); n > 0 && h.entries[n-1] == line {
+		h.cursor = len(h.entries)
+		return
+	}
+	h.entries = append(h.entries, line)
+	if len(h.entries) > historyLimit {
+		h.entries = h.entries[len(h.entries)-historyLimit:]
+	}
+	h.cursor = len(h.entries)
+}
+
+// Prev moves the recall cursor back one entry and returns it. ok is false
+// once the oldest entry has already been returned.
+func (h *history) Prev() (line string, ok bool) {
+	if h.cursor <= 0 {
+		return "", false
+	}
+	h.cursor--
+	return h.entries[h.cursor], true
+}
+
+// Next moves the recall cursor forward one entry. Once it runs past the
+// newest entry it returns an empty line, matching the "nothing recalled"
+// state most shells present.
+func (h *history) Next() (line string, ok bool) {
+	if h.cursor >= len(h.entries) {
+		return "", false
+	}
+	h.cursor++
+	if h.cursor == len(h.entries) {
+		return "", true
+	}
+	return h.entries[h.cursor], true
+}
+
+// All returns the recorded lines, oldest first.
+func (h *history) All() []string {
+	return h.entries
+}
@@ -0,0 +1,51 @@
+package repl
+
+import "testing"
+
+func TestHistoryPrevNext(t *testing.T) {
+	h := newHistory()
+	h.Add("let a = 1;")
+	h.Add("let b = 2;")
+
+	if line, ok := h.Prev(); !ok || line != "let b = 2;" {
+		t.Fatalf("Prev() = %q, %v; want %q, true", line, ok, "let b = 2;")
+	}
+	if line, ok := h.Prev(); !ok || line != "let a = 1;" {
+		t.Fatalf("Prev() = %q, %v; want %q, true", line, ok, "let a = 1;")
+	}
+	if _, ok := h.Prev(); ok {
+		t.Fatalf("Prev() beyond the oldest entry should return ok=false")
+	}
+	if line, ok := h.Next(); !ok || line != "let b = 2;" {
+		t.Fatalf("Next() = %q, %v; want %q, true", line, ok, "let b = 2;")
+	}
+}
+
+func TestHistorySkipsBlankAndRepeatedLines(t *testing.T) {
+	h := newHistory()
+	h.Add("")
+	h.Add("1 + 1")
+	h.Add("1 + 1")
+
+	if len(h.All()) != 1 {
+		t.Fatalf("All() = %v, want a single entry", h.All())
+	}
+}
+
+func TestBraceDelta(t *testing.T) {
+	tests := []struct {
+		line string
+		want int
+	}{
+		{"let add = fn(x, y) {", 1},
+		{"  x + y;", 0},
+		{"};", -1},
+		{"(1 + 2) * 3", 0},
+	}
+
+	for _, tt := range tests {
+		if got := braceDelta(tt.line); got != tt.want {
+			t.Errorf("braceDelta(%q) = %d, want %d", tt.line, got, tt.want)
+		}
+	}
+}
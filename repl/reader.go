@@ -0,0 +1,42 @@
+package repl
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// lineReader yields successive lines of raw input, optionally supporting
+// up/down history recall when the underlying stream is an interactive
+// terminal. Implementations that can't do recall (piped input, non-unix
+// platforms) still satisfy the interface by falling back to plain
+// line-buffered reads.
+type lineReader interface {
+	// ReadLine prints prompt and returns the next line of input. ok is
+	// false once the stream is exhausted.
+	ReadLine(prompt string) (line string, ok bool)
+}
+
+// scannerReader is the portable fallback: no history recall, just
+// bufio.Scanner over whatever was handed to repl.Start. This is also what
+// non-interactive input (pipes, `monkey < script.monkey`) uses, since there
+// is nothing to recall against.
+type scannerReader struct {
+	out     io.Writer
+	scanner *bufio.Scanner
+	hist    *history
+}
+
+func newScannerReader(in io.Reader, out io.Writer, hist *history) *scannerReader {
+	return &scannerReader{out: out, scanner: bufio.NewScanner(in), hist: hist}
+}
+
+func (r *scannerReader) ReadLine(prompt string) (string, bool) {
+	fmt.Fprint(r.out, prompt)
+	if !r.scanner.Scan() {
+		return "", false
+	}
+	line := r.scanner.Text()
+	r.hist.Add(line)
+	return line, true
+}
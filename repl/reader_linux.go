@@ -0,0 +1,115 @@
+//go:build linux
+
+package repl
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// newLineReader picks a raw-mode reader when in is an interactive terminal
+// (so arrow-key history recall works), and falls back to the portable
+// scanner otherwise.
+func newLineReader(in io.Reader, out io.Writer, hist *history) lineReader {
+	f, ok := in.(*os.File)
+	if !ok {
+		return newScannerReader(in, out, hist)
+	}
+	if _, err := unix.IoctlGetTermios(int(f.Fd()), unix.TCGETS); err != nil {
+		return newScannerReader(in, out, hist)
+	}
+	return &ttyReader{f: f, out: out, hist: hist}
+}
+
+// ttyReader reads a line at a time from a raw-mode terminal, redrawing the
+// line in place as the user edits it or recalls history with up/down.
+type ttyReader struct {
+	f    *os.File
+	out  io.Writer
+	hist *history
+	buf  [1]byte
+}
+
+// rawTermios switches the terminal to character-at-a-time, no-echo mode for
+// the duration of a single ReadLine call and restores it afterwards.
+func (r *ttyReader) rawTermios() (restore func(), err error) {
+	fd := int(r.f.Fd())
+	orig, err := unix.IoctlGetTermios(fd, unix.TCGETS)
+	if err != nil {
+		return nil, err
+	}
+	raw := *orig
+	raw.Lflag &^= unix.ECHO | unix.ICANON | unix.ISIG
+	raw.Cc[unix.VMIN] = 1
+	raw.Cc[unix.VTIME] = 0
+	if err := unix.IoctlSetTermios(fd, unix.TCSETS, &raw); err != nil {
+		return nil, err
+	}
+	return func() { unix.IoctlSetTermios(fd, unix.TCSETS, orig) }, nil
+}
+
+func (r *ttyReader) ReadLine(prompt string) (string, bool) {
+	restore, err := r.rawTermios()
+	if err != nil {
+		// Can't put the terminal in raw mode -- degrade to plain scanning.
+		return newScannerReader(r.f, r.out, r.hist).ReadLine(prompt)
+	}
+	defer restore()
+
+	io.WriteString(r.out, prompt)
+	var line []rune
+	redraw := func() {
+		io.WriteString(r.out, "\r\x1b[K"+prompt+string(line))
+	}
+
+	for {
+		n, err := r.f.Read(r.buf[:])
+		if n == 0 || err != nil {
+			return "", false
+		}
+		b := r.buf[0]
+		switch {
+		case b == '\r' || b == '\n':
+			io.WriteString(r.out, "\r\n")
+			s := string(line)
+			r.hist.Add(s)
+			return s, true
+		case b == 3: // Ctrl-C
+			return "", false
+		case b == 4 && len(line) == 0: // Ctrl-D on empty line
+			return "", false
+		case b == 127 || b == 8: // backspace
+			if len(line) > 0 {
+				line = line[:len(line)-1]
+				redraw()
+			}
+		case b == 0x1b: // escape sequence, expect '[' then 'A'/'B'
+			var seq [2]byte
+			if _, err := r.f.Read(seq[:1]); err != nil || seq[0] != '[' {
+				continue
+			}
+			if _, err := r.f.Read(seq[1:2]); err != nil {
+				continue
+			}
+			switch seq[1] {
+			case 'A': // up
+				if s, ok := r.hist.Prev(); ok {
+					line = []rune(s)
+					redraw()
+				}
+			case 'B': // down
+				if s, ok := r.hist.Next(); ok {
+					line = []rune(s)
+					redraw()
+				}
+			}
+		default:
+			if b >= 0x20 {
+				line = append(line, rune(b))
+				io.WriteString(r.out, string(b))
+			}
+		}
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !linux
+
+package repl
+
+import "io"
+
+// newLineReader falls back to the portable scanner on platforms where we
+// haven't implemented raw-mode terminal handling. History is still tracked
+// via :history, it just can't be recalled with the up/down arrow keys.
+func newLineReader(in io.Reader, out io.Writer, hist *history) lineReader {
+	return newScannerReader(in, out, hist)
+}
@@ -1,34 +1,320 @@
 package repl
 
 import (
-	"bufio"
 	"fmt"
 	"io"
+	"os"
+	"strings"
 
+	"monkey-lang/ast"
+	"monkey-lang/compiler"
+	"monkey-lang/evaluator"
 	"monkey-lang/lexer"
+	"monkey-lang/object"
+	"monkey-lang/parser"
 	"monkey-lang/token"
+	"monkey-lang/vm"
 )
 
 const PROMPT = ">> "
 
-// Start launches a simple token-printing REPL over the provided streams.
-func Start(in io.Reader, out io.Writer) {
-	scanner := bufio.NewScanner(in)
+// Mode selects which engine the REPL feeds parsed input into.
+type Mode string
+
+const (
+	// ModeTokens just lexes each submission and prints the token stream.
+	ModeTokens Mode = "tokens"
+	// ModeAST parses each submission and prints the resulting AST.
+	ModeAST Mode = "ast"
+	// ModeEval runs the tree-walking evaluator.
+	ModeEval Mode = "eval"
+	// ModeCompile compiles to bytecode and runs it on the VM.
+	ModeCompile Mode = "compile"
+)
+
+// Config controls how Start behaves.
+type Config struct {
+	// Mode selects the execution engine. Defaults to ModeCompile.
+	Mode Mode
+}
+
+// DefaultConfig returns the Config Start uses when none is given.
+func DefaultConfig() Config {
+	return Config{Mode: ModeCompile}
+}
+
+func (m Mode) valid() bool {
+	switch m {
+	case ModeTokens, ModeAST, ModeEval, ModeCompile:
+		return true
+	default:
+		return false
+	}
+}
+
+// Start runs the REPL over in/out until the input stream is exhausted or
+// :quit is entered. A single object.Environment (ModeEval) and a single
+// SymbolTable + constants pool (ModeCompile) are kept alive for the whole
+// session, so `let` bindings made on one line are visible on the next.
+func Start(in io.Reader, out io.Writer, cfg Config) {
+	if !cfg.Mode.valid() {
+		cfg = DefaultConfig()
+	}
+
+	hist := newHistory()
+	reader := newLineReader(in, out, hist)
+
+	session := &session{
+		mode: cfg.Mode,
+		env:  object.NewEnvironment(),
+		hist: hist,
+	}
+	session.resetCompiler()
 
 	for {
-		fmt.Fprint(out, PROMPT)
-		if !scanner.Scan() {
+		submission, ok := readSubmission(reader)
+		if !ok {
 			return
 		}
+		if submission == "" {
+			continue
+		}
+
+		if strings.HasPrefix(submission, ":") {
+			if !session.handleMeta(submission, out) {
+				return
+			}
+			continue
+		}
+
+		session.eval(submission, out)
+	}
+}
+
+// readSubmission accumulates lines from reader until braces/parens balance
+// and the last line doesn't end in a trailing backslash, so multi-line
+// function literals and block statements can be typed across prompts.
+func readSubmission(reader lineReader) (string, bool) {
+	var lines []string
+	prompt := PROMPT
+	depth := 0
+
+	for {
+		line, ok := reader.ReadLine(prompt)
+		if !ok {
+			return "", false
+		}
 
-		line := scanner.Text()
-		l := lexer.New(line)
+		continued := strings.HasSuffix(line, "\\")
+		line = strings.TrimSuffix(line, "\\")
+		depth += braceDelta(line)
+		lines = append(lines, line)
+
+		if depth <= 0 && !continued {
+			break
+		}
+		prompt = "... "
+	}
 
-		// Tokenize the line and print each token until EOF.
+	return strings.Join(lines, "\n"), true
+}
+
+func braceDelta(line string) int {
+	delta := 0
+	for _, r := range line {
+		switch r {
+		case '{', '(', '[':
+			delta++
+		case '}', ')', ']':
+			delta--
+		}
+	}
+	return delta
+}
+
+// session holds the state that must survive across prompts.
+type session struct {
+	mode Mode
+	env  *object.Environment
+	hist *history
+
+	constants   []object.Object
+	globals     []object.Object
+	symbolTable *compiler.SymbolTable
+
+	printer ast.Printer
+}
+
+func (s *session) resetCompiler() {
+	s.constants = []object.Object{}
+	s.globals = make([]object.Object, vm.GlobalsSize)
+	s.symbolTable = compiler.NewSymbolTable()
+	for i, name := range object.Builtins {
+		s.symbolTable.DefineBuiltin(i, name.Name)
+	}
+}
+
+func (s *session) eval(input string, out io.Writer) {
+	l := lexer.New(input)
+
+	if s.mode == ModeTokens {
 		for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
 			fmt.Fprintf(out, "%+v\n", tok)
 		}
+		return
+	}
+
+	p := parser.New(l)
+	program := p.ParseProgram()
+	if errs := p.Errors(); len(errs) > 0 {
+		printParseErrors(out, input, errs)
+		return
+	}
+
+	switch s.mode {
+	case ModeAST:
+		io.WriteString(out, s.printer.Print(program))
+		io.WriteString(out, "\n")
+
+	case ModeEval:
+		result := evaluator.Eval(program, s.env)
+		if result != nil {
+			io.WriteString(out, result.Inspect())
+			io.WriteString(out, "\n")
+		}
+
+	case ModeCompile:
+		ast.FoldConstants(program)
+
+		comp := compiler.NewWithState(s.symbolTable, s.constants)
+		if err := comp.Compile(program); err != nil {
+			fmt.Fprintf(out, "compilation failed:\n\t%s\n", err)
+			return
+		}
+
+		code := comp.Bytecode()
+		s.constants = code.Constants
+
+		machine := vm.NewWithGlobalsStore(code, s.globals)
+		if err := machine.Run(); err != nil {
+			fmt.Fprintf(out, "executing bytecode failed:\n\t%s\n", err)
+			return
+		}
+
+		top := machine.LastPoppedStackElem()
+		if top != nil {
+			io.WriteString(out, top.Inspect())
+			io.WriteString(out, "\n")
+		}
+	}
+}
+
+// printParseErrors renders each parser error together with the offending
+// source line and a caret under the reported column, when the error carries
+// position information in the conventional "line N:M: message" shape.
+func printParseErrors(out io.Writer, input string, errs []string) {
+	lines := strings.Split(input, "\n")
+	io.WriteString(out, "parser errors:\n")
+	for _, msg := range errs {
+		fmt.Fprintf(out, "\t%s\n", msg)
+		if lineNo, col, ok := parseErrorPosition(msg); ok && lineNo >= 1 && lineNo <= len(lines) {
+			src := lines[lineNo-1]
+			fmt.Fprintf(out, "\t%s\n", src)
+			fmt.Fprintf(out, "\t%s^\n", strings.Repeat(" ", col))
+		}
 	}
 }
 
+// parseErrorPosition extracts a 1-based line and 0-based column from a
+// "line %d:%d: ..." style parser error message, if present.
+func parseErrorPosition(msg string) (line, col int, ok bool) {
+	if !strings.HasPrefix(msg, "line ") {
+		return 0, 0, false
+	}
+	rest := strings.TrimPrefix(msg, "line ")
+	head, _, found := strings.Cut(rest, ": ")
+	if !found {
+		return 0, 0, false
+	}
+	parts := strings.SplitN(head, ":", 2)
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	if _, err := fmt.Sscanf(parts[0], "%d", &line); err != nil {
+		return 0, 0, false
+	}
+	if _, err := fmt.Sscanf(parts[1], "%d", &col); err != nil {
+		return 0, 0, false
+	}
+	return line, col, true
+}
+
+// handleMeta runs a leading-colon meta-command. It returns false when the
+// REPL should exit.
+func (s *session) handleMeta(cmd string, out io.Writer) bool {
+	fields := strings.Fields(cmd)
+	switch fields[0] {
+	case ":help":
+		io.WriteString(out, metaHelp)
+
+	case ":quit", ":exit":
+		return false
+
+	case ":mode":
+		if len(fields) < 2 {
+			fmt.Fprintf(out, "current mode: %s\n", s.mode)
+			return true
+		}
+		m := Mode(fields[1])
+		if !m.valid() {
+			fmt.Fprintf(out, "unknown mode %q (want tokens, ast, eval, or compile)\n", fields[1])
+			return true
+		}
+		s.mode = m
+		fmt.Fprintf(out, "switched to %s mode\n", m)
+
+	case ":parens":
+		s.printer.Parens = !s.printer.Parens
+		fmt.Fprintf(out, "ast mode parens: %v\n", s.printer.Parens)
+
+	case ":reset":
+		s.env = object.NewEnvironment()
+		s.resetCompiler()
+		io.WriteString(out, "environment reset\n")
+
+	case ":load":
+		if len(fields) < 2 {
+			io.WriteString(out, "usage: :load <file>\n")
+			return true
+		}
+		data, err := os.ReadFile(fields[1])
+		if err != nil {
+			fmt.Fprintf(out, "couldn't read %s: %s\n", fields[1], err)
+			return true
+		}
+		s.eval(string(data), out)
+
+	case ":history":
+		entries := s.hist.All()
+		if len(entries) == 0 {
+			io.WriteString(out, "(no history yet)\n")
+			return true
+		}
+		for i, line := range entries {
+			fmt.Fprintf(out, "%4d  %s\n", i+1, line)
+		}
+
+	default:
+		fmt.Fprintf(out, "unknown command %q, try :help\n", fields[0])
+	}
+	return true
+}
 
+const metaHelp = `:help            show this message
+:mode [name]     show or switch the execution mode (tokens, ast, eval, compile)
+:parens          toggle fully-parenthesized output in ast mode
+:reset           clear all let-bindings and start a fresh environment
+:load <file>     read and evaluate a file in the current mode
+:history         list previously submitted lines, oldest first
+:quit, :exit     leave the REPL
+`
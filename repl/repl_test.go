@@ -0,0 +1,208 @@
+package repl
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func newTestSession(mode Mode) (*session, *bytes.Buffer) {
+	s := &session{mode: mode, hist: newHistory()}
+	s.resetCompiler()
+	return s, &bytes.Buffer{}
+}
+
+func TestHandleMetaHelp(t *testing.T) {
+	s, out := newTestSession(ModeCompile)
+
+	if ok := s.handleMeta(":help", out); !ok {
+		t.Fatalf("handleMeta(:help) = false, want true (should not quit)")
+	}
+	if !strings.Contains(out.String(), ":mode [name]") {
+		t.Fatalf(":help output = %q, want it to mention :mode", out.String())
+	}
+}
+
+func TestHandleMetaQuitAndExit(t *testing.T) {
+	for _, cmd := range []string{":quit", ":exit"} {
+		s, out := newTestSession(ModeCompile)
+		if ok := s.handleMeta(cmd, out); ok {
+			t.Fatalf("handleMeta(%s) = true, want false (should quit)", cmd)
+		}
+	}
+}
+
+func TestHandleMetaMode(t *testing.T) {
+	s, out := newTestSession(ModeCompile)
+
+	if ok := s.handleMeta(":mode eval", out); !ok {
+		t.Fatalf("handleMeta(:mode eval) returned false")
+	}
+	if s.mode != ModeEval {
+		t.Fatalf("mode = %s, want %s", s.mode, ModeEval)
+	}
+	if !strings.Contains(out.String(), "switched to eval mode") {
+		t.Fatalf("output = %q, want a confirmation message", out.String())
+	}
+
+	out.Reset()
+	if ok := s.handleMeta(":mode nonsense", out); !ok {
+		t.Fatalf("handleMeta(:mode nonsense) returned false")
+	}
+	if s.mode != ModeEval {
+		t.Fatalf("mode changed to %s after an invalid :mode, want it to stay %s", s.mode, ModeEval)
+	}
+	if !strings.Contains(out.String(), "unknown mode") {
+		t.Fatalf("output = %q, want an unknown-mode error", out.String())
+	}
+
+	out.Reset()
+	s.handleMeta(":mode", out)
+	if !strings.Contains(out.String(), "current mode: eval") {
+		t.Fatalf("bare :mode output = %q, want it to report the current mode", out.String())
+	}
+}
+
+func TestHandleMetaParens(t *testing.T) {
+	s, out := newTestSession(ModeAST)
+
+	if s.printer.Parens {
+		t.Fatalf("printer.Parens = true before any :parens toggle, want false")
+	}
+
+	if ok := s.handleMeta(":parens", out); !ok {
+		t.Fatalf("handleMeta(:parens) returned false")
+	}
+	if !s.printer.Parens {
+		t.Fatalf("printer.Parens = false after :parens, want true")
+	}
+	if !strings.Contains(out.String(), "ast mode parens: true") {
+		t.Fatalf("output = %q, want a confirmation message", out.String())
+	}
+
+	out.Reset()
+	s.handleMeta(":parens", out)
+	if s.printer.Parens {
+		t.Fatalf("printer.Parens = true after a second :parens toggle, want false")
+	}
+}
+
+func TestHandleMetaReset(t *testing.T) {
+	s, out := newTestSession(ModeCompile)
+	s.constants = append(s.constants, nil) // pretend something had been compiled
+
+	if ok := s.handleMeta(":reset", out); !ok {
+		t.Fatalf("handleMeta(:reset) returned false")
+	}
+	if len(s.constants) != 0 {
+		t.Fatalf("constants = %v after :reset, want empty", s.constants)
+	}
+	if !strings.Contains(out.String(), "environment reset") {
+		t.Fatalf("output = %q, want a confirmation message", out.String())
+	}
+}
+
+func TestHandleMetaLoadMissingFile(t *testing.T) {
+	s, out := newTestSession(ModeCompile)
+
+	if ok := s.handleMeta(":load /no/such/file.monkey", out); !ok {
+		t.Fatalf("handleMeta(:load) returned false")
+	}
+	if !strings.Contains(out.String(), "couldn't read") {
+		t.Fatalf("output = %q, want a read error", out.String())
+	}
+}
+
+func TestHandleMetaLoadUsage(t *testing.T) {
+	s, out := newTestSession(ModeCompile)
+
+	s.handleMeta(":load", out)
+	if !strings.Contains(out.String(), "usage: :load <file>") {
+		t.Fatalf("output = %q, want a usage message", out.String())
+	}
+}
+
+func TestHandleMetaHistory(t *testing.T) {
+	s, out := newTestSession(ModeCompile)
+
+	s.handleMeta(":history", out)
+	if !strings.Contains(out.String(), "no history yet") {
+		t.Fatalf("output = %q, want a no-history message", out.String())
+	}
+
+	out.Reset()
+	s.hist.Add("let a = 1;")
+	s.hist.Add("a + 1;")
+	s.handleMeta(":history", out)
+
+	got := out.String()
+	if !strings.Contains(got, "let a = 1;") || !strings.Contains(got, "a + 1;") {
+		t.Fatalf(":history output = %q, want both prior submissions listed", got)
+	}
+}
+
+func TestHandleMetaUnknownCommand(t *testing.T) {
+	s, out := newTestSession(ModeCompile)
+
+	if ok := s.handleMeta(":nonsense", out); !ok {
+		t.Fatalf("handleMeta(:nonsense) returned false")
+	}
+	if !strings.Contains(out.String(), `unknown command ":nonsense"`) {
+		t.Fatalf("output = %q, want an unknown-command error", out.String())
+	}
+}
+
+func TestReadSubmissionAccumulatesUnbalancedInput(t *testing.T) {
+	hist := newHistory()
+	reader := newScannerReader(strings.NewReader("let add = fn(x, y) {\n  x + y;\n};\n"), &bytes.Buffer{}, hist)
+
+	got, ok := readSubmission(reader)
+	if !ok {
+		t.Fatalf("readSubmission() ok = false, want true")
+	}
+	want := "let add = fn(x, y) {\n  x + y;\n};"
+	if got != want {
+		t.Fatalf("readSubmission() = %q, want %q", got, want)
+	}
+}
+
+func TestParseErrorPosition(t *testing.T) {
+	tests := []struct {
+		msg      string
+		line     int
+		col      int
+		ok       bool
+		testName string
+	}{
+		{"line 3:7: expected next token to be ), got EOF instead", 3, 7, true, "well formed"},
+		{"no prefix parse function for + found", 0, 0, false, "no line prefix"},
+		{"line abc: bad", 0, 0, false, "non-numeric line"},
+	}
+
+	for _, tt := range tests {
+		line, col, ok := parseErrorPosition(tt.msg)
+		if ok != tt.ok || line != tt.line || col != tt.col {
+			t.Errorf("%s: parseErrorPosition(%q) = (%d, %d, %v), want (%d, %d, %v)",
+				tt.testName, tt.msg, line, col, ok, tt.line, tt.col, tt.ok)
+		}
+	}
+}
+
+func TestPrintParseErrors(t *testing.T) {
+	var out bytes.Buffer
+	input := "let x = ;\nlet y = 5;"
+	errs := []string{"line 1:9: no prefix parse function for ; found"}
+
+	printParseErrors(&out, input, errs)
+
+	got := out.String()
+	if !strings.Contains(got, errs[0]) {
+		t.Fatalf("output = %q, want it to include the error message", got)
+	}
+	if !strings.Contains(got, "let x = ;") {
+		t.Fatalf("output = %q, want it to echo the offending source line", got)
+	}
+	if !strings.Contains(got, strings.Repeat(" ", 9)+"^") {
+		t.Fatalf("output = %q, want a caret under column 9", got)
+	}
+}